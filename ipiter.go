@@ -0,0 +1,129 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// parseExcludePrefixes parses a comma-separated list of CIDRs (e.g. RFC1918
+// or bogon ranges) to skip during iteration.
+func parseExcludePrefixes(raw string) ([]netip.Prefix, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var prefixes []netip.Prefix
+	for _, s := range strings.Split(raw, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -exclude CIDR %q: %w", s, err)
+		}
+		prefixes = append(prefixes, p)
+	}
+	return prefixes, nil
+}
+
+// isExcluded reports whether addr falls within any of the excluded prefixes.
+func isExcluded(addr netip.Addr, excluded []netip.Prefix) bool {
+	for _, p := range excluded {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// iterateCIDR streams the addresses within cidr onto the returned channel,
+// skipping any that fall within excluded.
+//
+// IPv4 prefixes, and IPv6 prefixes at least sampleThreshold bits long, are
+// enumerated in full. Broader IPv6 prefixes are far too large to walk, so
+// instead sampleCount addresses are chosen at random from within them.
+func iterateCIDR(cidr string, excluded []netip.Prefix, sampleCount, sampleThreshold int) (<-chan netip.Addr, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return nil, err
+	}
+	prefix = prefix.Masked()
+
+	if prefix.Addr().Is6() && prefix.Bits() < sampleThreshold {
+		if sampleCount <= 0 {
+			return nil, fmt.Errorf("IPv6 prefix %s is broader than the -sample-threshold of /%d and cannot be enumerated in full; pass -sample N to scan a random sample of it instead", prefix, sampleThreshold)
+		}
+		out := make(chan netip.Addr)
+		go func() {
+			defer close(out)
+			sampleAddresses(prefix, sampleCount, excluded, out)
+		}()
+		return out, nil
+	}
+
+	out := make(chan netip.Addr)
+
+	go func() {
+		defer close(out)
+		for addr := prefix.Addr(); ; {
+			if !isExcluded(addr, excluded) {
+				out <- addr
+			}
+			next := addr.Next()
+			if !next.IsValid() || !prefix.Contains(next) {
+				break
+			}
+			addr = next
+		}
+	}()
+	return out, nil
+}
+
+// sampleAddresses emits up to count distinct, non-excluded random addresses
+// drawn from within prefix's host range.
+func sampleAddresses(prefix netip.Prefix, count int, excluded []netip.Prefix, out chan<- netip.Addr) {
+	if count <= 0 {
+		return
+	}
+
+	base := prefix.Addr().As16()
+	seen := make(map[netip.Addr]struct{}, count)
+	maxAttempts := count * 10
+	for attempts := 0; len(seen) < count && attempts < maxAttempts; attempts++ {
+		addr := randomAddrIn(base, prefix.Bits())
+		if _, ok := seen[addr]; ok {
+			continue
+		}
+		if isExcluded(addr, excluded) {
+			continue
+		}
+		seen[addr] = struct{}{}
+		out <- addr
+	}
+}
+
+// randomAddrIn returns a random IPv6 address that keeps base's first
+// prefixBits bits and randomizes the rest.
+func randomAddrIn(base [16]byte, prefixBits int) netip.Addr {
+	result := base
+	var randomBytes [16]byte
+	rand.Read(randomBytes[:])
+
+	for i := 0; i < 16; i++ {
+		byteStart := i * 8
+		switch {
+		case byteStart+8 <= prefixBits:
+			// Entirely within the network portion: keep base as-is.
+		case byteStart >= prefixBits:
+			result[i] = randomBytes[i]
+		default:
+			networkBits := prefixBits - byteStart
+			mask := byte(0xFF << (8 - networkBits))
+			result[i] = (base[i] & mask) | (randomBytes[i] &^ mask)
+		}
+	}
+	return netip.AddrFrom16(result)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestIterateCIDREnumeratesIPv4InFull(t *testing.T) {
+	ch, err := iterateCIDR("192.0.2.0/30", nil, 0, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var addrs []netip.Addr
+	for addr := range ch {
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) != 4 {
+		t.Fatalf("expected 4 addresses for a /30, got %d", len(addrs))
+	}
+}
+
+func TestIterateCIDRRefusesBroadIPv6WithoutSample(t *testing.T) {
+	_, err := iterateCIDR("2001:db8::/32", nil, 0, 100)
+	if err == nil {
+		t.Fatal("expected an error for a broad IPv6 prefix with -sample=0, got nil")
+	}
+}
+
+func TestIterateCIDRSamplesBroadIPv6(t *testing.T) {
+	ch, err := iterateCIDR("2001:db8::/32", nil, 5, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var addrs []netip.Addr
+	for addr := range ch {
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) != 5 {
+		t.Fatalf("expected 5 sampled addresses, got %d", len(addrs))
+	}
+}
@@ -2,18 +2,16 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"flag"
 	"fmt"
-	"log"
-	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"regexp"
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/PuerkitoBio/goquery"
 )
 
 var (
@@ -23,77 +21,59 @@ var (
 	filterRegex = flag.String("f", "", "Regex or string to filter out unwanted domains")
 	matchRegex  = flag.String("m", "", "Regex or string to display only domains matching the specified pattern")
 	verbose     = flag.Bool("v", false, "Show debug logs")
-)
 
-// fetchHostnamesFromShodan retrieves hostnames associated with the given IP from Shodan.
-func fetchHostnamesFromShodan(ip string, client *http.Client) []string {
-	if *verbose {
-		log.Printf("Fetching data for IP: %s\n", ip)
-	}
-	url := fmt.Sprintf("https://www.shodan.io/host/%s", ip)
-	resp, err := client.Get(url)
-	if err != nil {
-		if *verbose {
-			log.Printf("Error fetching data for IP %s: %v\n", ip, err)
-		}
-		return nil
-	}
-	defer resp.Body.Close()
+	outputFormat = flag.String("o", "txt", "Output format: txt|json|jsonl|csv")
+	outputFile   = flag.String("out", "", "Write output to this file instead of stdout")
 
-	if resp.StatusCode != http.StatusOK {
-		if *verbose {
-			log.Printf("Non-200 status code for IP %s: %d\n", ip, resp.StatusCode)
-		}
-		return nil
-	}
+	shodanEnabled = flag.Bool("shodan", true, "Enable the Shodan resolver")
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		if *verbose {
-			log.Printf("Error parsing HTML for IP %s: %v\n", ip, err)
-		}
-		return nil
-	}
+	securityTrailsAPIKey = flag.String("securitytrails-key", os.Getenv("SECURITYTRAILS_API_KEY"), "SecurityTrails API key (env SECURITYTRAILS_API_KEY)")
+	securityTrailsRate   = flag.Duration("securitytrails-rate", time.Second, "Minimum delay between SecurityTrails requests")
 
-	var hostnames []string
-	// Assuming hostnames are constructed using text nodes around <b> tags.
-	doc.Find("b").Each(func(i int, s *goquery.Selection) {
-		text := s.Text()
-		prev := s.Get(0).PrevSibling
-		if prev != nil && strings.TrimSpace(prev.Data) != "" {
-			domain := strings.TrimSpace(prev.Data) + text
-			hostnames = append(hostnames, domain)
-		}
-	})
+	virusTotalAPIKey = flag.String("virustotal-key", os.Getenv("VIRUSTOTAL_API_KEY"), "VirusTotal API key (env VIRUSTOTAL_API_KEY)")
+	virusTotalRate   = flag.Duration("virustotal-rate", time.Second, "Minimum delay between VirusTotal requests")
 
-	if *verbose && len(hostnames) == 0 {
-		log.Printf("No hostnames found for IP %s\n", ip)
-	}
+	rapidDNSEnabled = flag.Bool("rapiddns", false, "Enable the RapidDNS resolver")
+	rapidDNSRate    = flag.Duration("rapiddns-rate", time.Second, "Minimum delay between RapidDNS requests")
 
-	return hostnames
-}
+	hackerTargetEnabled = flag.Bool("hackertarget", false, "Enable the HackerTarget resolver")
+	hackerTargetAPIKey  = flag.String("hackertarget-key", os.Getenv("HACKERTARGET_API_KEY"), "HackerTarget API key for higher rate limits (env HACKERTARGET_API_KEY)")
+	hackerTargetRate    = flag.Duration("hackertarget-rate", time.Second, "Minimum delay between HackerTarget requests")
 
-// incrementIP increases an IP address by one.
-func incrementIP(ip net.IP) {
-	for j := len(ip) - 1; j >= 0; j-- {
-		ip[j]++
-		if ip[j] > 0 {
-			break
-		}
-	}
-}
+	crtshEnabled = flag.Bool("crtsh", false, "Enable the crt.sh certificate-transparency resolver")
+	crtshRate    = flag.Duration("crtsh-rate", time.Second, "Minimum delay between crt.sh requests")
 
-// processCIDR scans all IP addresses in the given CIDR using a worker pool and sends found hostnames to the channel.
-func processCIDR(cidr string, ch chan<- string, filter *regexp.Regexp, match *regexp.Regexp, client *http.Client, wg *sync.WaitGroup) {
+	sysRDNSEnabled = flag.Bool("sysrdns", false, "Enable system reverse DNS (PTR) lookups")
+
+	rdnsMode       = flag.Bool("rdns", false, "Enable mass reverse-DNS mode using a custom DNS server pool")
+	dnsServersFile = flag.String("dns-servers", "", "File of \"ip[:port]\" DNS servers to use with -rdns (one per line, default port 53)")
+
+	proxiesFile      = flag.String("p", "", "File of \"ip:port\" SOCKS5 proxies to rotate through for HTTP fetches")
+	requestsPerProxy = flag.Int("n", 1, "Number of requests to send through each proxy before rotating to the next")
+
+	sampleCount     = flag.Int("sample", 0, "Number of addresses to randomly sample from large IPv6 prefixes instead of full enumeration (0 disables sampling)")
+	sampleThreshold = flag.Int("sample-threshold", 100, "IPv6 prefixes shorter than this many bits are sampled instead of fully enumerated")
+	excludeFlag     = flag.String("exclude", "", "Comma-separated CIDRs to exclude from iteration (e.g. RFC1918/bogon ranges)")
+
+	recursiveMode = flag.Bool("recursive", false, "Resolve discovered hostnames, look up their announcing ASN, and scan the resulting prefixes too")
+	maxDepth      = flag.Int("depth", 1, "Maximum recursion depth for -recursive")
+	maxCidrs      = flag.Int("max-cidrs", 100, "Maximum number of additional prefixes -recursive may enqueue (0 = unlimited)")
+)
+
+// processCIDR scans the addresses in the given CIDR using a worker pool,
+// fanning each address out to every enabled resolver and sending found
+// hostnames to the channel. IPv4 prefixes and small-enough IPv6 prefixes are
+// enumerated in full; broader IPv6 prefixes are randomly sampled (see
+// iterateCIDR).
+func processCIDR(cidr string, ch chan<- Result, filter *regexp.Regexp, match *regexp.Regexp, resolvers []Resolver, excluded []netip.Prefix, depth int, state *scanState, wg *sync.WaitGroup) {
 	defer wg.Done()
-	_, ipNet, err := net.ParseCIDR(cidr)
+
+	addrChan, err := iterateCIDR(cidr, excluded, *sampleCount, *sampleThreshold)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing CIDR %s: %v\n", cidr, err)
 		return
 	}
 
-	// Channel to distribute IP addresses to workers.
-	ipChan := make(chan net.IP)
 	var localWg sync.WaitGroup
 
 	// Launch a fixed number of workers.
@@ -101,34 +81,72 @@ func processCIDR(cidr string, ch chan<- string, filter *regexp.Regexp, match *re
 		localWg.Add(1)
 		go func() {
 			defer localWg.Done()
-			for ip := range ipChan {
-				hostnames := fetchHostnamesFromShodan(ip.String(), client)
-				for _, hostname := range hostnames {
-					if filter != nil && filter.MatchString(hostname) {
+			for addr := range addrChan {
+				for _, result := range resolveAll(addr.String(), resolvers) {
+					if filter != nil && filter.MatchString(result.Hostname) {
 						continue
 					}
-					if match != nil && !match.MatchString(hostname) {
+					if match != nil && !match.MatchString(result.Hostname) {
 						continue
 					}
-					// Send the hostname immediately.
-					ch <- hostname
+					result.CIDR = cidr
+					// Send the result immediately.
+					ch <- result
+
+					if *recursiveMode && depth < *maxDepth {
+						for _, prefix := range prefixesForHostname(result.Hostname) {
+							if !state.claim(prefix) {
+								continue
+							}
+							wg.Add(1)
+							go processCIDR(prefix, ch, filter, match, resolvers, excluded, depth+1, state, wg)
+						}
+					}
 				}
 			}
 		}()
 	}
 
-	// Enqueue IP addresses.
-	for ip := ipNet.IP.Mask(ipNet.Mask); ipNet.Contains(ip); {
-		// Create a copy of ip because it is mutated in the loop.
-		ipCopy := make(net.IP, len(ip))
-		copy(ipCopy, ip)
-		ipChan <- ipCopy
-		incrementIP(ip)
-	}
-	close(ipChan)
 	localWg.Wait()
 }
 
+// resolveAll queries every resolver for the given IP in parallel and
+// deduplicates the combined set of hostnames, tagging each with the source
+// that found it.
+func resolveAll(ip string, resolvers []Resolver) []Result {
+	type found struct {
+		source    Source
+		hostnames []string
+	}
+	results := make([]found, len(resolvers))
+
+	var wg sync.WaitGroup
+	for i, resolver := range resolvers {
+		wg.Add(1)
+		go func(i int, resolver Resolver) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			results[i] = found{source: resolver.Name(), hostnames: resolver.Resolve(ctx, ip)}
+		}(i, resolver)
+	}
+	wg.Wait()
+
+	now := time.Now()
+	seen := make(map[string]struct{})
+	var out []Result
+	for _, r := range results {
+		for _, hostname := range r.hostnames {
+			if _, ok := seen[hostname]; ok {
+				continue
+			}
+			seen[hostname] = struct{}{}
+			out = append(out, Result{IP: ip, Hostname: hostname, Source: r.source, Timestamp: now})
+		}
+	}
+	return out
+}
+
 // getCIDRList reads CIDRs from a file, flag, or standard input.
 func getCIDRList() []string {
 	var cidrList []string
@@ -188,10 +206,16 @@ func main() {
 		match = regexp.MustCompile(*matchRegex)
 	}
 
+	excluded, err := parseExcludePrefixes(*excludeFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	cidrList := getCIDRList()
 
-	// Channel for results (hostnames) to be printed as soon as they are found.
-	ch := make(chan string, *concurrency*10)
+	// Channel for results to be printed as soon as they are found.
+	ch := make(chan Result, *concurrency*10)
 	var wg sync.WaitGroup
 
 	client := &http.Client{
@@ -203,10 +227,52 @@ func main() {
 		},
 	}
 
+	var proxies *proxyPool
+	if *proxiesFile != "" {
+		var err error
+		proxies, err = loadProxyPool(*proxiesFile, *requestsPerProxy, 5*time.Second)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading proxies: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	resolvers := buildResolvers(client, proxies)
+
+	if *rdnsMode {
+		if *dnsServersFile == "" {
+			fmt.Fprintln(os.Stderr, "Error: -rdns requires --dns-servers <file>")
+			os.Exit(1)
+		}
+		servers, err := loadDNSServers(*dnsServersFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading DNS servers: %v\n", err)
+			os.Exit(1)
+		}
+		if len(servers) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: no DNS servers loaded from --dns-servers file")
+			os.Exit(1)
+		}
+		resolvers = append(resolvers, newMassRDNSResolver(newDNSServerPool(servers)))
+	}
+
+	if len(resolvers) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no resolvers enabled. Enable at least one (e.g. -shodan or -rdns).")
+		os.Exit(1)
+	}
+
+	var state *scanState
+	if *recursiveMode {
+		state = newScanState(*maxCidrs)
+		for _, cidr := range cidrList {
+			state.seed(cidr)
+		}
+	}
+
 	// Start processing each CIDR concurrently.
 	for _, cidr := range cidrList {
 		wg.Add(1)
-		go processCIDR(cidr, ch, filter, match, client, &wg)
+		go processCIDR(cidr, ch, filter, match, resolvers, excluded, 0, state, &wg)
 	}
 
 	// Close the results channel when done.
@@ -215,12 +281,12 @@ func main() {
 		close(ch)
 	}()
 
-	// Print unique hostnames as they are received.
-	uniqueResults := make(map[string]struct{})
-	for result := range ch {
-		if _, exists := uniqueResults[result]; !exists {
-			uniqueResults[result] = struct{}{}
-			fmt.Println(result)
-		}
+	out, closeOut, err := openOutput(*outputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening output file: %v\n", err)
+		os.Exit(1)
 	}
+	defer closeOut()
+
+	writeResults(ch, *outputFormat, out)
 }
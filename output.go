@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Result is a single hostname discovery, carrying enough provenance for
+// downstream tooling to consume without post-processing.
+type Result struct {
+	IP        string    `json:"ip"`
+	CIDR      string    `json:"cidr"`
+	Hostname  string    `json:"hostname"`
+	Source    Source    `json:"source"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// openOutput returns the writer results should be printed to: stdout, or the
+// file named by -out.
+func openOutput(path string) (io.Writer, func(), error) {
+	if path == "" {
+		return os.Stdout, func() {}, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { f.Close() }, nil
+}
+
+// writeResults drains ch, deduplicating by hostname, and formats each unique
+// result according to format ("txt", "json", "jsonl" or "csv").
+func writeResults(ch <-chan Result, format string, w io.Writer) {
+	seen := make(map[string]struct{})
+	unique := func(r Result) bool {
+		if _, ok := seen[r.Hostname]; ok {
+			return false
+		}
+		seen[r.Hostname] = struct{}{}
+		return true
+	}
+
+	switch format {
+	case "json":
+		all := []Result{}
+		for r := range ch {
+			if unique(r) {
+				all = append(all, r)
+			}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(all); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON output: %v\n", err)
+		}
+
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		for r := range ch {
+			if !unique(r) {
+				continue
+			}
+			if err := enc.Encode(r); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSONL record: %v\n", err)
+			}
+		}
+
+	case "csv":
+		cw := csv.NewWriter(w)
+		defer cw.Flush()
+		cw.Write([]string{"ip", "cidr", "hostname", "source", "timestamp"})
+		for r := range ch {
+			if !unique(r) {
+				continue
+			}
+			cw.Write([]string{r.IP, r.CIDR, r.Hostname, string(r.Source), r.Timestamp.Format(time.RFC3339)})
+		}
+
+	default: // "txt"
+		for r := range ch {
+			if unique(r) {
+				fmt.Fprintln(w, r.Hostname)
+			}
+		}
+	}
+}
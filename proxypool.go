@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// proxyQuarantine is how long a proxy is taken out of rotation after it
+// starts failing or getting rate-limited.
+const proxyQuarantine = 5 * time.Minute
+
+// proxyFailureThreshold is how many consecutive failures/429s a proxy may
+// accumulate before it is quarantined.
+const proxyFailureThreshold = 3
+
+// proxyPool rotates HTTP clients across a set of SOCKS5 proxies, sending a
+// fixed number of requests through each before moving to the next, and
+// quarantining proxies that fail repeatedly.
+type proxyPool struct {
+	mu      sync.Mutex
+	entries []*proxyEntry
+
+	requestsPerProxy int
+	current          int
+	usedOnCurrent    int
+}
+
+type proxyEntry struct {
+	addr             string
+	client           *http.Client
+	failures         int
+	quarantinedUntil time.Time
+}
+
+// loadProxyPool builds an http.Client per SOCKS5 proxy address listed in
+// path (one "ip:port" entry per line).
+func loadProxyPool(path string, requestsPerProxy int, timeout time.Duration) (*proxyPool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	pool := &proxyPool{requestsPerProxy: requestsPerProxy}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		addr := strings.TrimSpace(scanner.Text())
+		if addr == "" {
+			continue
+		}
+		dialer, err := proxy.SOCKS5("tcp", addr, nil, proxy.Direct)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error configuring proxy %s: %v\n", addr, err)
+			continue
+		}
+		client := &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{Dial: dialer.Dial},
+		}
+		pool.entries = append(pool.entries, &proxyEntry{addr: addr, client: client})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(pool.entries) == 0 {
+		return nil, fmt.Errorf("no usable proxies loaded from %s", path)
+	}
+	return pool, nil
+}
+
+// next returns the http.Client to use for the next request, rotating to the
+// following proxy every requestsPerProxy calls and skipping quarantined
+// proxies where possible.
+func (p *proxyPool) next() *http.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := 0; i < len(p.entries); i++ {
+		entry := p.entries[p.current]
+		if time.Now().Before(entry.quarantinedUntil) {
+			p.advance()
+			continue
+		}
+
+		p.usedOnCurrent++
+		if p.usedOnCurrent >= p.requestsPerProxy {
+			p.advance()
+		}
+		return entry.client
+	}
+
+	// Every proxy is quarantined; fall back to the current one anyway.
+	return p.entries[p.current].client
+}
+
+// advance moves to the next proxy and resets its usage counter.
+func (p *proxyPool) advance() {
+	p.current = (p.current + 1) % len(p.entries)
+	p.usedOnCurrent = 0
+}
+
+// recordResult quarantines the proxy behind client after too many
+// consecutive failures or HTTP 429 responses, and clears its failure count
+// on success.
+func (p *proxyPool) recordResult(client *http.Client, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, entry := range p.entries {
+		if entry.client != client {
+			continue
+		}
+		if !failed {
+			entry.failures = 0
+			return
+		}
+		entry.failures++
+		if entry.failures >= proxyFailureThreshold {
+			entry.quarantinedUntil = time.Now().Add(proxyQuarantine)
+			entry.failures = 0
+		}
+		return
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import "time"
+
+// rateLimiter throttles calls made against a single source to at most one
+// per interval, shared across all worker goroutines using that source.
+type rateLimiter struct {
+	ticker *time.Ticker
+}
+
+// newRateLimiter returns a limiter that allows one call per interval, or nil
+// (a no-op limiter) when interval is zero or negative.
+func newRateLimiter(interval time.Duration) *rateLimiter {
+	if interval <= 0 {
+		return nil
+	}
+	return &rateLimiter{ticker: time.NewTicker(interval)}
+}
+
+// wait blocks until the next call is allowed. A nil limiter never blocks.
+func (r *rateLimiter) wait() {
+	if r == nil {
+		return
+	}
+	<-r.ticker.C
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxConsecutiveFailures is how many consecutive failures a DNS server may
+// accumulate before it is evicted from the rotation.
+const maxConsecutiveFailures = 5
+
+// dnsServerPool performs PTR lookups against a user-supplied pool of DNS
+// servers, rotating between them at random and evicting servers that fail
+// too many times in a row.
+type dnsServerPool struct {
+	mu       sync.Mutex
+	servers  []string
+	failures map[string]int
+
+	// inFlight coalesces concurrent lookups of the same IP, so that
+	// overlapping CIDRs share one query's result instead of each querying
+	// (or, for the loser of the race, silently missing out on) it.
+	inFlightMu sync.Mutex
+	inFlight   map[string]*ptrLookup
+
+	queryTimeout time.Duration
+}
+
+// ptrLookup is the shared result of a single in-flight PTR lookup.
+type ptrLookup struct {
+	done      chan struct{}
+	hostnames []string
+}
+
+// loadDNSServers reads "ip[:port]" entries from path, defaulting to port 53
+// when no port is given.
+func loadDNSServers(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var servers []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(line); err != nil {
+			line = net.JoinHostPort(line, "53")
+		}
+		servers = append(servers, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+func newDNSServerPool(servers []string) *dnsServerPool {
+	return &dnsServerPool{
+		servers:      append([]string(nil), servers...),
+		failures:     make(map[string]int),
+		inFlight:     make(map[string]*ptrLookup),
+		queryTimeout: 5 * time.Second,
+	}
+}
+
+// pick returns a random server still in rotation, or "" if the pool has been
+// exhausted by evictions.
+func (p *dnsServerPool) pick() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.servers) == 0 {
+		return ""
+	}
+	return p.servers[rand.Intn(len(p.servers))]
+}
+
+// recordResult clears a server's failure count on success, or evicts it once
+// it has failed maxConsecutiveFailures times in a row.
+func (p *dnsServerPool) recordResult(server string, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ok {
+		delete(p.failures, server)
+		return
+	}
+
+	p.failures[server]++
+	if p.failures[server] < maxConsecutiveFailures {
+		return
+	}
+	for i, s := range p.servers {
+		if s == server {
+			p.servers = append(p.servers[:i], p.servers[i+1:]...)
+			break
+		}
+	}
+	delete(p.failures, server)
+}
+
+// resolverFor builds a net.Resolver that dials a pool server chosen at
+// random over UDP for every query, recording which server was picked in
+// usedServer. A UDP "dial" never actually contacts the remote host, so its
+// error is not a useful failure signal; the caller must report the real
+// outcome (e.g. of LookupAddr) via recordResult itself.
+func (p *dnsServerPool) resolverFor(usedServer *string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			server := p.pick()
+			if server == "" {
+				return nil, fmt.Errorf("dns server pool exhausted")
+			}
+			*usedServer = server
+			d := net.Dialer{Timeout: p.queryTimeout}
+			return d.DialContext(ctx, "udp", server)
+		},
+	}
+}
+
+// lookupPTR performs a PTR lookup for ip using the pool. If another
+// goroutine is already looking up the same IP, it waits for and shares that
+// lookup's result instead of querying (or coming back empty-handed) again.
+func (p *dnsServerPool) lookupPTR(ctx context.Context, ip string) []string {
+	p.inFlightMu.Lock()
+	if existing, ok := p.inFlight[ip]; ok {
+		p.inFlightMu.Unlock()
+		<-existing.done
+		return existing.hostnames
+	}
+	lookup := &ptrLookup{done: make(chan struct{})}
+	p.inFlight[ip] = lookup
+	p.inFlightMu.Unlock()
+
+	defer func() {
+		p.inFlightMu.Lock()
+		delete(p.inFlight, ip)
+		p.inFlightMu.Unlock()
+		close(lookup.done)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, p.queryTimeout)
+	defer cancel()
+
+	var usedServer string
+	names, err := p.resolverFor(&usedServer).LookupAddr(ctx, ip)
+	if usedServer != "" {
+		// The actual query (not the UDP "dial", which never touches the
+		// remote host) is the real signal of whether this server is alive.
+		p.recordResult(usedServer, err == nil)
+	}
+	if err != nil {
+		return nil
+	}
+
+	hostnames := make([]string, 0, len(names))
+	for _, name := range names {
+		hostnames = append(hostnames, strings.TrimSuffix(name, "."))
+	}
+	lookup.hostnames = hostnames
+	return hostnames
+}
+
+// massRDNSResolver performs PTR lookups against a custom pool of DNS servers
+// instead of the system resolver, for bulk reverse-DNS sweeps.
+type massRDNSResolver struct {
+	pool *dnsServerPool
+}
+
+func newMassRDNSResolver(pool *dnsServerPool) *massRDNSResolver {
+	return &massRDNSResolver{pool: pool}
+}
+
+func (r *massRDNSResolver) Name() Source { return SourceMassRDNS }
+
+func (r *massRDNSResolver) Resolve(ctx context.Context, ip string) []string {
+	return r.pool.lookupPTR(ctx, ip)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDNSServerPoolEvictsAfterConsecutiveFailures(t *testing.T) {
+	pool := newDNSServerPool([]string{"203.0.113.1:53"})
+
+	for i := 0; i < maxConsecutiveFailures-1; i++ {
+		pool.recordResult("203.0.113.1:53", false)
+		if pool.pick() == "" {
+			t.Fatalf("server evicted too early, after %d failures", i+1)
+		}
+	}
+
+	pool.recordResult("203.0.113.1:53", false)
+	if got := pool.pick(); got != "" {
+		t.Fatalf("expected server to be evicted after %d consecutive failures, pick() returned %q", maxConsecutiveFailures, got)
+	}
+}
+
+func TestDNSServerPoolSuccessResetsFailures(t *testing.T) {
+	pool := newDNSServerPool([]string{"203.0.113.1:53"})
+
+	for i := 0; i < maxConsecutiveFailures-1; i++ {
+		pool.recordResult("203.0.113.1:53", false)
+	}
+	pool.recordResult("203.0.113.1:53", true)
+
+	pool.recordResult("203.0.113.1:53", false)
+	if pool.pick() == "" {
+		t.Fatal("a single failure after a success should not evict the server")
+	}
+}
+
+func TestDNSServerPoolLookupPTRCoalescesConcurrentCallers(t *testing.T) {
+	pool := newDNSServerPool([]string{"203.0.113.1:53"})
+
+	// Simulate a lookup for this IP that another goroutine already has
+	// in flight.
+	lookup := &ptrLookup{done: make(chan struct{})}
+	pool.inFlight["198.51.100.5"] = lookup
+
+	resultCh := make(chan []string, 1)
+	go func() {
+		resultCh <- pool.lookupPTR(context.Background(), "198.51.100.5")
+	}()
+
+	select {
+	case <-resultCh:
+		t.Fatal("second caller returned before the in-flight lookup completed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	lookup.hostnames = []string{"example.com"}
+	close(lookup.done)
+
+	select {
+	case got := <-resultCh:
+		if len(got) != 1 || got[0] != "example.com" {
+			t.Fatalf("expected coalesced result [example.com], got %v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second caller never returned after the in-flight lookup completed")
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+// scanState tracks CIDRs already scanned (or queued) across the whole run,
+// so -recursive doesn't loop back into prefixes already covered, and
+// enforces the -max-cidrs budget on how many it may enqueue.
+type scanState struct {
+	mu      sync.Mutex
+	scanned map[string]struct{}
+
+	enqueued int
+	maxCidrs int
+}
+
+func newScanState(maxCidrs int) *scanState {
+	return &scanState{scanned: make(map[string]struct{}), maxCidrs: maxCidrs}
+}
+
+// claim marks cidr as scanned and reports whether it was newly claimed,
+// i.e. not already scanned and still under the -max-cidrs budget. Use this
+// for prefixes discovered via recursion.
+func (s *scanState) claim(cidr string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.scanned[cidr]; ok {
+		return false
+	}
+	if s.maxCidrs > 0 && s.enqueued >= s.maxCidrs {
+		return false
+	}
+	s.scanned[cidr] = struct{}{}
+	s.enqueued++
+	return true
+}
+
+// seed marks one of the user's own input CIDRs as already scanned, without
+// counting it against the -max-cidrs budget. -max-cidrs bounds what
+// -recursive itself is allowed to discover, not the user's own input.
+func (s *scanState) seed(cidr string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scanned[cidr] = struct{}{}
+}
+
+// cymruWhoisLookup queries Team Cymru's whois service for the BGP prefix
+// announcing ip, returning it as a CIDR string.
+func cymruWhoisLookup(ip string) (string, error) {
+	conn, err := net.DialTimeout("tcp", "whois.cymru.com:43", 5*time.Second)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if _, err := fmt.Fprintf(conn, " -v %s\n", ip); err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(conn)
+	var lastLine string
+	for scanner.Scan() {
+		lastLine = scanner.Text()
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	// Response rows look like:
+	// AS | IP | BGP Prefix | CC | Registry | Allocated | AS Name
+	fields := strings.Split(lastLine, "|")
+	if len(fields) < 3 {
+		return "", fmt.Errorf("unexpected whois.cymru.com response: %q", lastLine)
+	}
+	prefix := strings.TrimSpace(fields[2])
+	if prefix == "" {
+		return "", fmt.Errorf("no BGP prefix found for IP %s", ip)
+	}
+	return prefix, nil
+}
+
+// prefixesForHostname resolves hostname to its A/AAAA records and looks up
+// the BGP prefix announcing each one, deduplicated.
+func prefixesForHostname(hostname string) []string {
+	ips, err := net.LookupIP(hostname)
+	if err != nil {
+		if *verbose {
+			log.Printf("[recursive] error resolving %s: %v\n", hostname, err)
+		}
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	var prefixes []string
+	for _, ip := range ips {
+		prefix, err := cymruWhoisLookup(ip.String())
+		if err != nil {
+			if *verbose {
+				log.Printf("[recursive] error looking up ASN for %s (%s): %v\n", hostname, ip, err)
+			}
+			continue
+		}
+		if _, err := netip.ParsePrefix(prefix); err != nil {
+			continue
+		}
+		if _, ok := seen[prefix]; ok {
+			continue
+		}
+		seen[prefix] = struct{}{}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes
+}
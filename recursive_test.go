@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestScanStateSeedDoesNotConsumeBudget(t *testing.T) {
+	state := newScanState(1)
+	state.seed("192.0.2.0/24")
+	state.seed("198.51.100.0/24")
+
+	if !state.claim("203.0.113.0/24") {
+		t.Fatal("seeding two CIDRs should not have consumed the -max-cidrs=1 budget")
+	}
+}
+
+func TestScanStateClaimEnforcesBudget(t *testing.T) {
+	state := newScanState(1)
+
+	if !state.claim("192.0.2.0/24") {
+		t.Fatal("first claim should succeed under budget 1")
+	}
+	if state.claim("198.51.100.0/24") {
+		t.Fatal("second claim should fail once the -max-cidrs budget is exhausted")
+	}
+}
+
+func TestScanStateClaimRejectsDuplicates(t *testing.T) {
+	state := newScanState(0)
+
+	if !state.claim("192.0.2.0/24") {
+		t.Fatal("first claim of a CIDR should succeed")
+	}
+	if state.claim("192.0.2.0/24") {
+		t.Fatal("re-claiming the same CIDR should fail")
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"net/http"
+)
+
+// Source identifies which passive-DNS provider produced a hostname.
+type Source string
+
+const (
+	SourceShodan         Source = "shodan"
+	SourceSecurityTrails Source = "securitytrails"
+	SourceVirusTotal     Source = "virustotal"
+	SourceRapidDNS       Source = "rapiddns"
+	SourceHackerTarget   Source = "hackertarget"
+	SourceCrtSh          Source = "crtsh"
+	SourceRDNS           Source = "rdns"
+	SourceMassRDNS       Source = "massrdns"
+)
+
+// Resolver looks up hostnames associated with a single IP address from one
+// passive-DNS source.
+type Resolver interface {
+	Name() Source
+	Resolve(ctx context.Context, ip string) []string
+}
+
+// buildResolvers constructs the set of resolvers enabled via flags/env vars.
+// proxies is nil unless -p was given, in which case HTTP resolvers that
+// support it (currently Shodan) rotate requests across it.
+func buildResolvers(client *http.Client, proxies *proxyPool) []Resolver {
+	var resolvers []Resolver
+
+	if *shodanEnabled {
+		resolvers = append(resolvers, newShodanResolver(client, proxies))
+	}
+	if *securityTrailsAPIKey != "" {
+		resolvers = append(resolvers, newSecurityTrailsResolver(client, *securityTrailsAPIKey, *securityTrailsRate))
+	}
+	if *virusTotalAPIKey != "" {
+		resolvers = append(resolvers, newVirusTotalResolver(client, *virusTotalAPIKey, *virusTotalRate))
+	}
+	if *rapidDNSEnabled {
+		resolvers = append(resolvers, newRapidDNSResolver(client, *rapidDNSRate))
+	}
+	if *hackerTargetEnabled {
+		resolvers = append(resolvers, newHackerTargetResolver(client, *hackerTargetAPIKey, *hackerTargetRate))
+	}
+	if *crtshEnabled {
+		resolvers = append(resolvers, newCrtShResolver(client, *crtshRate))
+	}
+	if *sysRDNSEnabled {
+		resolvers = append(resolvers, newSystemRDNSResolver())
+	}
+
+	return resolvers
+}
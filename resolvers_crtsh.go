@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// crtShResolver queries crt.sh's certificate-transparency search for
+// certificates whose SAN list references the target IP, and returns the
+// other hostnames listed on the same certificates.
+type crtShResolver struct {
+	client  *http.Client
+	limiter *rateLimiter
+}
+
+func newCrtShResolver(client *http.Client, rate time.Duration) *crtShResolver {
+	return &crtShResolver{client: client, limiter: newRateLimiter(rate)}
+}
+
+func (r *crtShResolver) Name() Source { return SourceCrtSh }
+
+type crtShEntry struct {
+	NameValue string `json:"name_value"`
+}
+
+// Resolve retrieves hostnames found on certificates referencing the given IP from crt.sh.
+func (r *crtShResolver) Resolve(ctx context.Context, ip string) []string {
+	r.limiter.wait()
+
+	url := fmt.Sprintf("https://crt.sh/?q=%s&output=json", ip)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		if *verbose {
+			log.Printf("[crtsh] error fetching data for IP %s: %v\n", ip, err)
+		}
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if *verbose {
+			log.Printf("[crtsh] non-200 status code for IP %s: %d\n", ip, resp.StatusCode)
+		}
+		return nil
+	}
+
+	var entries []crtShEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		if *verbose {
+			log.Printf("[crtsh] error decoding response for IP %s: %v\n", ip, err)
+		}
+		return nil
+	}
+
+	var hostnames []string
+	for _, entry := range entries {
+		for _, name := range strings.Split(entry.NameValue, "\n") {
+			name = strings.TrimSpace(name)
+			if name != "" {
+				hostnames = append(hostnames, name)
+			}
+		}
+	}
+	return hostnames
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// hackerTargetResolver queries HackerTarget's reverse IP lookup API, which
+// returns one "hostname,ip" pair per line of plain text.
+type hackerTargetResolver struct {
+	client  *http.Client
+	apiKey  string
+	limiter *rateLimiter
+}
+
+func newHackerTargetResolver(client *http.Client, apiKey string, rate time.Duration) *hackerTargetResolver {
+	return &hackerTargetResolver{client: client, apiKey: apiKey, limiter: newRateLimiter(rate)}
+}
+
+func (r *hackerTargetResolver) Name() Source { return SourceHackerTarget }
+
+// Resolve retrieves hostnames that share the given IP from HackerTarget.
+func (r *hackerTargetResolver) Resolve(ctx context.Context, ip string) []string {
+	r.limiter.wait()
+
+	url := fmt.Sprintf("https://api.hackertarget.com/reverseiplookup/?q=%s", ip)
+	if r.apiKey != "" {
+		url += "&apikey=" + r.apiKey
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		if *verbose {
+			log.Printf("[hackertarget] error fetching data for IP %s: %v\n", ip, err)
+		}
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if *verbose {
+			log.Printf("[hackertarget] non-200 status code for IP %s: %d\n", ip, resp.StatusCode)
+		}
+		return nil
+	}
+
+	var hostnames []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "error") {
+			continue
+		}
+		hostnames = append(hostnames, line)
+	}
+
+	return hostnames
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// rapidDNSResolver scrapes RapidDNS's free reverse-IP lookup page.
+type rapidDNSResolver struct {
+	client  *http.Client
+	limiter *rateLimiter
+}
+
+func newRapidDNSResolver(client *http.Client, rate time.Duration) *rapidDNSResolver {
+	return &rapidDNSResolver{client: client, limiter: newRateLimiter(rate)}
+}
+
+func (r *rapidDNSResolver) Name() Source { return SourceRapidDNS }
+
+// Resolve retrieves hostnames that currently resolve to the given IP from RapidDNS.
+func (r *rapidDNSResolver) Resolve(ctx context.Context, ip string) []string {
+	r.limiter.wait()
+
+	url := fmt.Sprintf("https://rapiddns.io/s/%s?full=1", ip)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		if *verbose {
+			log.Printf("[rapiddns] error fetching data for IP %s: %v\n", ip, err)
+		}
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if *verbose {
+			log.Printf("[rapiddns] non-200 status code for IP %s: %d\n", ip, resp.StatusCode)
+		}
+		return nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		if *verbose {
+			log.Printf("[rapiddns] error parsing HTML for IP %s: %v\n", ip, err)
+		}
+		return nil
+	}
+
+	var hostnames []string
+	doc.Find("table#table tbody tr td:first-child").Each(func(i int, s *goquery.Selection) {
+		hostname := strings.TrimSpace(s.Text())
+		if hostname != "" {
+			hostnames = append(hostnames, hostname)
+		}
+	})
+
+	return hostnames
+}
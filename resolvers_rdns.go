@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"strings"
+)
+
+// systemRDNSResolver performs a plain PTR lookup using the system/Go resolver.
+type systemRDNSResolver struct {
+	resolver *net.Resolver
+}
+
+func newSystemRDNSResolver() *systemRDNSResolver {
+	return &systemRDNSResolver{resolver: net.DefaultResolver}
+}
+
+func (r *systemRDNSResolver) Name() Source { return SourceRDNS }
+
+// Resolve performs a PTR lookup for the given IP.
+func (r *systemRDNSResolver) Resolve(ctx context.Context, ip string) []string {
+	names, err := r.resolver.LookupAddr(ctx, ip)
+	if err != nil {
+		if *verbose {
+			log.Printf("[rdns] error looking up PTR for IP %s: %v\n", ip, err)
+		}
+		return nil
+	}
+
+	hostnames := make([]string, 0, len(names))
+	for _, name := range names {
+		hostnames = append(hostnames, strings.TrimSuffix(name, "."))
+	}
+	return hostnames
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// securityTrailsResolver queries SecurityTrails' nearby-IPs endpoint for
+// hostnames known to share infrastructure with the target IP.
+type securityTrailsResolver struct {
+	client  *http.Client
+	apiKey  string
+	limiter *rateLimiter
+}
+
+func newSecurityTrailsResolver(client *http.Client, apiKey string, rate time.Duration) *securityTrailsResolver {
+	return &securityTrailsResolver{client: client, apiKey: apiKey, limiter: newRateLimiter(rate)}
+}
+
+func (r *securityTrailsResolver) Name() Source { return SourceSecurityTrails }
+
+type securityTrailsResponse struct {
+	Blocks map[string]struct {
+		Hostnames []string `json:"hostnames"`
+	} `json:"blocks"`
+}
+
+// Resolve retrieves hostnames associated with the given IP from SecurityTrails.
+func (r *securityTrailsResolver) Resolve(ctx context.Context, ip string) []string {
+	r.limiter.wait()
+
+	url := fmt.Sprintf("https://api.securitytrails.com/v1/ips/nearby/%s", ip)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+	req.Header.Set("APIKEY", r.apiKey)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		if *verbose {
+			log.Printf("[securitytrails] error fetching data for IP %s: %v\n", ip, err)
+		}
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if *verbose {
+			log.Printf("[securitytrails] non-200 status code for IP %s: %d\n", ip, resp.StatusCode)
+		}
+		return nil
+	}
+
+	var data securityTrailsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		if *verbose {
+			log.Printf("[securitytrails] error decoding response for IP %s: %v\n", ip, err)
+		}
+		return nil
+	}
+
+	var hostnames []string
+	for _, block := range data.Blocks {
+		hostnames = append(hostnames, block.Hostnames...)
+	}
+	return hostnames
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// shodanResolver scrapes hostnames from Shodan's public host page. Shodan
+// rate-limits aggressively by source IP, so requests are optionally spread
+// across a rotating pool of SOCKS5 proxies.
+type shodanResolver struct {
+	client  *http.Client
+	proxies *proxyPool
+}
+
+func newShodanResolver(client *http.Client, proxies *proxyPool) *shodanResolver {
+	return &shodanResolver{client: client, proxies: proxies}
+}
+
+func (r *shodanResolver) Name() Source { return SourceShodan }
+
+// Resolve retrieves hostnames associated with the given IP from Shodan.
+func (r *shodanResolver) Resolve(ctx context.Context, ip string) []string {
+	if *verbose {
+		log.Printf("[shodan] fetching data for IP: %s\n", ip)
+	}
+
+	client := r.client
+	if r.proxies != nil {
+		client = r.proxies.next()
+	}
+
+	url := fmt.Sprintf("https://www.shodan.io/host/%s", ip)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if r.proxies != nil {
+			r.proxies.recordResult(client, true)
+		}
+		if *verbose {
+			log.Printf("[shodan] error fetching data for IP %s: %v\n", ip, err)
+		}
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if r.proxies != nil {
+			r.proxies.recordResult(client, resp.StatusCode == http.StatusTooManyRequests)
+		}
+		if *verbose {
+			log.Printf("[shodan] non-200 status code for IP %s: %d\n", ip, resp.StatusCode)
+		}
+		return nil
+	}
+
+	if r.proxies != nil {
+		r.proxies.recordResult(client, false)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		if *verbose {
+			log.Printf("[shodan] error parsing HTML for IP %s: %v\n", ip, err)
+		}
+		return nil
+	}
+
+	var hostnames []string
+	// Assuming hostnames are constructed using text nodes around <b> tags.
+	doc.Find("b").Each(func(i int, s *goquery.Selection) {
+		text := s.Text()
+		prev := s.Get(0).PrevSibling
+		if prev != nil && strings.TrimSpace(prev.Data) != "" {
+			domain := strings.TrimSpace(prev.Data) + text
+			hostnames = append(hostnames, domain)
+		}
+	})
+
+	if *verbose && len(hostnames) == 0 {
+		log.Printf("[shodan] no hostnames found for IP %s\n", ip)
+	}
+
+	return hostnames
+}
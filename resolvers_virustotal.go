@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// virusTotalResolver queries VirusTotal's passive DNS resolutions endpoint
+// for hostnames that have historically resolved to the target IP.
+type virusTotalResolver struct {
+	client  *http.Client
+	apiKey  string
+	limiter *rateLimiter
+}
+
+func newVirusTotalResolver(client *http.Client, apiKey string, rate time.Duration) *virusTotalResolver {
+	return &virusTotalResolver{client: client, apiKey: apiKey, limiter: newRateLimiter(rate)}
+}
+
+func (r *virusTotalResolver) Name() Source { return SourceVirusTotal }
+
+type virusTotalResolutionsResponse struct {
+	Data []struct {
+		Attributes struct {
+			HostName string `json:"host_name"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// Resolve retrieves hostnames historically associated with the given IP from VirusTotal.
+func (r *virusTotalResolver) Resolve(ctx context.Context, ip string) []string {
+	r.limiter.wait()
+
+	url := fmt.Sprintf("https://www.virustotal.com/api/v3/ip_addresses/%s/resolutions", ip)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+	req.Header.Set("x-apikey", r.apiKey)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		if *verbose {
+			log.Printf("[virustotal] error fetching data for IP %s: %v\n", ip, err)
+		}
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if *verbose {
+			log.Printf("[virustotal] non-200 status code for IP %s: %d\n", ip, resp.StatusCode)
+		}
+		return nil
+	}
+
+	var data virusTotalResolutionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		if *verbose {
+			log.Printf("[virustotal] error decoding response for IP %s: %v\n", ip, err)
+		}
+		return nil
+	}
+
+	var hostnames []string
+	for _, entry := range data.Data {
+		if entry.Attributes.HostName != "" {
+			hostnames = append(hostnames, entry.Attributes.HostName)
+		}
+	}
+	return hostnames
+}